@@ -0,0 +1,268 @@
+package quick
+
+import (
+	"testing"
+
+	"github.com/jeffotoni/quick/middleware/cors"
+)
+
+// TestQuick_AutoOptions verifies that a plain route with no explicit
+// OPTIONS handler gets one synthesized with the right Allow header.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_AutoOptions
+func TestQuick_AutoOptions(t *testing.T) {
+	q := New()
+	q.Get("/items", func(c *Ctx) error {
+		return c.Status(200).String("ok")
+	})
+	if err := q.AutoOptions(); err != nil {
+		t.Fatalf("AutoOptions: %v", err)
+	}
+
+	res, err := q.Qtest(QuickTestOptions{
+		Method: MethodOptions,
+		URI:    "/items",
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+
+	if res.StatusCode() != 204 {
+		t.Errorf("expected status 204, but got %d", res.StatusCode())
+	}
+
+	allow := res.Response.Header.Get("Allow")
+	if allow != "GET, OPTIONS" {
+		t.Errorf("expected Allow 'GET, OPTIONS', but got %q", allow)
+	}
+}
+
+// TestQuick_AutoOptions_NonGETPattern verifies that AutoOptions rejects
+// a route it can't safely group under its pattern, instead of silently
+// producing an incomplete Allow header when GetRoute() reports an empty
+// Pattern for a non-GET route (see ExampleQuick_GetRoute).
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_AutoOptions_NonGETPattern
+func TestQuick_AutoOptions_NonGETPattern(t *testing.T) {
+	q := New()
+	q.Post("/widgets", func(c *Ctx) error {
+		return c.Status(201).String("created")
+	})
+
+	routes := q.GetRoute()
+	if len(routes) != 1 || routes[0].Pattern != "" {
+		t.Skipf("GetRoute() now reports Pattern for POST routes (got %q); AutoOptions' guard is no longer exercised by this test", routes[0].Pattern)
+	}
+
+	if err := q.AutoOptions(); err == nil {
+		t.Errorf("expected AutoOptions to reject a route with no reported pattern, got nil error")
+	}
+}
+
+// TestQuick_CORS_OneCall verifies that Quick.CORS registers both the
+// wrapped handler and its OPTIONS preflight route in a single call.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_CORS_OneCall
+func TestQuick_CORS_OneCall(t *testing.T) {
+	policy := cors.Policy{
+		AllowOrigins: []string{"https://example.com"},
+		AllowMethods: []string{"GET"},
+	}
+
+	q := New()
+	if err := q.CORS(MethodGet, "/api/widgets", func(c *Ctx) error {
+		return c.Status(200).String("widgets")
+	}, policy); err != nil {
+		t.Fatalf("CORS: %v", err)
+	}
+
+	res, err := q.Qtest(QuickTestOptions{
+		Method:  MethodGet,
+		URI:     "/api/widgets",
+		Headers: map[string]string{"Origin": "https://example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+	if res.StatusCode() != 200 {
+		t.Errorf("expected status 200, but got %d", res.StatusCode())
+	}
+	if got := res.Response.Header.Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("expected Access-Control-Allow-Origin 'https://example.com', but got %q", got)
+	}
+
+	res, err = q.Qtest(QuickTestOptions{
+		Method: MethodOptions,
+		URI:    "/api/widgets",
+		Headers: map[string]string{
+			"Origin":                        "https://example.com",
+			"Access-Control-Request-Method": "GET",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+	if res.StatusCode() != 204 {
+		t.Errorf("expected preflight status 204, but got %d", res.StatusCode())
+	}
+}
+
+// TestQuick_CORS_RejectsDisallowedOrigin verifies that an actual
+// (non-OPTIONS) request from an origin the policy doesn't allow gets a
+// 403 without reaching the handler, the same as cors.Policy.Middleware
+// does for an app- or group-wide policy.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_CORS_RejectsDisallowedOrigin
+func TestQuick_CORS_RejectsDisallowedOrigin(t *testing.T) {
+	policy := cors.Policy{
+		AllowOrigins: []string{"https://example.com"},
+		AllowMethods: []string{"GET"},
+	}
+
+	q := New()
+	called := false
+	if err := q.CORS(MethodGet, "/api/widgets", func(c *Ctx) error {
+		called = true
+		return c.Status(200).String("widgets")
+	}, policy); err != nil {
+		t.Fatalf("CORS: %v", err)
+	}
+
+	res, err := q.Qtest(QuickTestOptions{
+		Method:  MethodGet,
+		URI:     "/api/widgets",
+		Headers: map[string]string{"Origin": "https://evil.example"},
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+
+	if res.StatusCode() != 403 {
+		t.Errorf("expected status 403, but got %d", res.StatusCode())
+	}
+	if called {
+		t.Errorf("expected the handler not to run for a rejected origin")
+	}
+	if got := res.Response.Header.Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no Access-Control-Allow-Origin for a rejected origin, got %q", got)
+	}
+}
+
+// TestQuick_RouteCORS_Preflight table-drives a handful of preflight
+// requests through a per-route CORS policy, asserting every expected
+// header is present and that non-matching origins are rejected without
+// any CORS headers leaking through.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_RouteCORS_Preflight
+func TestQuick_RouteCORS_Preflight(t *testing.T) {
+	policy := cors.Policy{
+		AllowOrigins:  []string{"https://example.com"},
+		AllowMethods:  []string{"GET", "POST"},
+		AllowHeaders:  []string{"Content-Type", "X-Api-Key"},
+		ExposeHeaders: []string{"X-Request-Id"},
+		MaxAge:        600,
+	}
+
+	q := New()
+	_, preflight := q.RouteCORS(policy)
+	q.Options("/api/widgets", preflight)
+
+	tests := []struct {
+		name           string
+		origin         string
+		requestMethod  string
+		requestHeaders string
+		wantStatus     int
+		wantCORS       bool
+	}{
+		{
+			name:           "allowed origin and method",
+			origin:         "https://example.com",
+			requestMethod:  "POST",
+			requestHeaders: "Content-Type, X-Api-Key",
+			wantStatus:     204,
+			wantCORS:       true,
+		},
+		{
+			name:          "disallowed origin",
+			origin:        "https://evil.example",
+			requestMethod: "POST",
+			wantStatus:    403,
+			wantCORS:      false,
+		},
+		{
+			name:          "disallowed method",
+			origin:        "https://example.com",
+			requestMethod: "DELETE",
+			wantStatus:    403,
+			wantCORS:      false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			headers := map[string]string{
+				"Origin":                        tc.origin,
+				"Access-Control-Request-Method": tc.requestMethod,
+			}
+			if tc.requestHeaders != "" {
+				headers["Access-Control-Request-Headers"] = tc.requestHeaders
+			}
+
+			res, err := q.Qtest(QuickTestOptions{
+				Method:  MethodOptions,
+				URI:     "/api/widgets",
+				Headers: headers,
+			})
+			if err != nil {
+				t.Fatalf("Qtest: %v", err)
+			}
+
+			if res.StatusCode() != tc.wantStatus {
+				t.Errorf("expected status %d, but got %d", tc.wantStatus, res.StatusCode())
+			}
+
+			h := res.Response.Header
+			if tc.wantCORS {
+				if h.Get("Access-Control-Allow-Origin") != tc.origin {
+					t.Errorf("expected Access-Control-Allow-Origin %q, but got %q", tc.origin, h.Get("Access-Control-Allow-Origin"))
+				}
+				if h.Get("Access-Control-Allow-Methods") == "" {
+					t.Errorf("expected Access-Control-Allow-Methods to be set")
+				}
+				if h.Get("Access-Control-Expose-Headers") != "X-Request-Id" {
+					t.Errorf("expected Access-Control-Expose-Headers 'X-Request-Id', but got %q", h.Get("Access-Control-Expose-Headers"))
+				}
+				if h.Get("Access-Control-Max-Age") != "600" {
+					t.Errorf("expected Access-Control-Max-Age '600', but got %q", h.Get("Access-Control-Max-Age"))
+				}
+				vary := h.Values("Vary")
+				for _, want := range []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"} {
+					found := false
+					for _, v := range vary {
+						if v == want {
+							found = true
+						}
+					}
+					if !found {
+						t.Errorf("expected Vary to include %q, got %v", want, vary)
+					}
+				}
+			} else {
+				if h.Get("Access-Control-Allow-Origin") != "" {
+					t.Errorf("expected no Access-Control-Allow-Origin for a rejected preflight, got %q", h.Get("Access-Control-Allow-Origin"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package quick
+
+import "github.com/jeffotoni/quick/middleware/session"
+
+// Session returns the session named name for the current request. When
+// name is omitted it defaults to "session". The session must have been
+// made available earlier in the chain by session.Sessions; calling
+// Session without that middleware installed returns a nil session and
+// http.ErrNoCookie, so handlers should check the error rather than
+// assume a non-nil result.
+//
+// The cookie backing the session is only decoded the first time Session
+// is called for a given request, and whatever was loaded is written back
+// automatically right before the response headers are sent.
+func (c *Ctx) Session(name ...string) (*session.Session, error) {
+	sessName := "session"
+	if len(name) > 0 {
+		sessName = name[0]
+	}
+	return session.FromRequest(c.Request, sessName)
+}
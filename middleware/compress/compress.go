@@ -0,0 +1,406 @@
+// Package compress provides response compression middleware for Quick,
+// negotiating gzip, deflate, or brotli against the request's
+// Accept-Encoding header.
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Config controls how the compress middleware behaves.
+type Config struct {
+	// Level is the compression level passed to the chosen encoder. Its
+	// meaning follows compress/flate's scale (-2..9); 0 means "use the
+	// package default" for whichever encoder is selected.
+	Level int
+
+	// MinLength is the minimum response size, in bytes, before
+	// compression kicks in. Responses smaller than this are written
+	// through unmodified. Defaults to 1024 when zero.
+	MinLength int
+
+	// Types restricts compression to these content types (exact value
+	// or "type/*" prefix). When empty, every content type is eligible
+	// except the well-known pre-compressed ones listed in
+	// defaultSkippedTypes.
+	Types []string
+
+	// Encodings lists the encodings this middleware is allowed to use,
+	// in preference order when the client's Accept-Encoding does not
+	// disambiguate. Defaults to []string{"br", "gzip", "deflate"}.
+	Encodings []string
+}
+
+var defaultEncodings = []string{"br", "gzip", "deflate"}
+
+// defaultSkippedTypes are content types that are already compressed, so
+// spending CPU to compress them again would only add latency.
+var defaultSkippedTypes = []string{
+	"image/", "video/", "audio/",
+	"application/zip", "application/gzip", "application/x-gzip",
+	"application/x-brotli", "application/pdf", "font/woff", "font/woff2",
+}
+
+func (c Config) withDefaults() Config {
+	if c.MinLength <= 0 {
+		c.MinLength = 1024
+	}
+	if len(c.Encodings) == 0 {
+		c.Encodings = defaultEncodings
+	}
+	return c
+}
+
+// New wraps next, compressing eligible responses with whichever
+// encoding cfg and the request's Accept-Encoding header negotiate to.
+func New(cfg Config) func(http.Handler) http.Handler {
+	cfg = cfg.withDefaults()
+	pools := newEncoderPools(cfg.Level)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accepted := parseAcceptEncoding(r.Header.Get("Accept-Encoding"))
+			if rejectsEverything(accepted) {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+
+			encoding := negotiate(accepted, cfg.Encodings)
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				cfg:            cfg,
+				encoding:       encoding,
+				pools:          pools,
+				hasRange:       r.Header.Get("Range") != "",
+			}
+			defer cw.Close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// compressWriter buffers the start of a response so it can decide,
+// once it knows the content type and has seen at least MinLength bytes
+// (or the handler finishes, whichever comes first), whether compression
+// is worthwhile. Once that decision is made it is final for the rest of
+// the response.
+type compressWriter struct {
+	http.ResponseWriter
+	cfg      Config
+	encoding string
+	pools    *encoderPools
+	hasRange bool
+
+	status      int
+	wroteHeader bool
+	buf         []byte
+	decided     bool
+	enc         io.WriteCloser // nil once decided means "pass through"
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	// Defer the actual WriteHeader call until we know whether we're
+	// compressing, since that changes Content-Encoding/Content-Length.
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.decided {
+		if w.enc != nil {
+			return w.enc.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.cfg.MinLength {
+		w.decide()
+	}
+	return len(p), nil
+}
+
+// Close flushes any buffered bytes (deciding now, if it hasn't already)
+// and releases the pooled encoder.
+func (w *compressWriter) Close() error {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		w.decide()
+	}
+	if w.enc != nil {
+		err := w.enc.Close()
+		w.pools.release(w.encoding, w.enc)
+		return err
+	}
+	return nil
+}
+
+// decide picks compressed vs pass-through based on the buffered bytes,
+// status code, and negotiated content type, then flushes the buffer. A
+// 206 Partial Content response, or any request carrying a Range header,
+// is never compressed: compressing a byte range would invalidate the
+// Content-Range offsets the client asked for.
+func (w *compressWriter) decide() {
+	w.decided = true
+
+	contentType := w.ResponseWriter.Header().Get("Content-Type")
+	eligible := w.encoding != "" &&
+		w.status != http.StatusNoContent &&
+		w.status != http.StatusPartialContent &&
+		!w.hasRange &&
+		len(w.buf) >= w.cfg.MinLength &&
+		isCompressible(contentType, w.cfg.Types)
+
+	if !eligible {
+		w.ResponseWriter.WriteHeader(w.status)
+		if len(w.buf) > 0 {
+			_, _ = w.ResponseWriter.Write(w.buf)
+			w.buf = nil
+		}
+		return
+	}
+
+	h := w.ResponseWriter.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Encoding", w.encoding)
+	addVary(h, "Accept-Encoding")
+	w.ResponseWriter.WriteHeader(w.status)
+
+	w.enc = w.pools.acquire(w.encoding, w.ResponseWriter)
+	if len(w.buf) > 0 {
+		_, _ = w.enc.Write(w.buf)
+		w.buf = nil
+	}
+}
+
+func addVary(h http.Header, value string) {
+	for _, v := range h.Values("Vary") {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return
+		}
+	}
+	h.Add("Vary", value)
+}
+
+func isCompressible(contentType string, allow []string) bool {
+	ct := contentType
+	if i := strings.Index(ct, ";"); i >= 0 {
+		ct = ct[:i]
+	}
+	ct = strings.TrimSpace(ct)
+
+	if len(allow) > 0 {
+		for _, t := range allow {
+			if matchesType(ct, t) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, skip := range defaultSkippedTypes {
+		if strings.HasPrefix(ct, skip) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesType(ct, pattern string) bool {
+	if strings.HasSuffix(pattern, "/*") {
+		return strings.HasPrefix(ct, strings.TrimSuffix(pattern, "*"))
+	}
+	return ct == pattern
+}
+
+// acceptedEncoding is one element of a parsed Accept-Encoding header.
+type acceptedEncoding struct {
+	name string
+	q    float64
+}
+
+func parseAcceptEncoding(header string) []acceptedEncoding {
+	if header == "" {
+		return nil
+	}
+
+	parts := strings.Split(header, ",")
+	out := make([]acceptedEncoding, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, q := part, 1.0
+		if i := strings.Index(part, ";"); i >= 0 {
+			name = strings.TrimSpace(part[:i])
+			if qv, ok := parseQValue(part[i+1:]); ok {
+				q = qv
+			}
+		}
+		out = append(out, acceptedEncoding{name: strings.ToLower(name), q: q})
+	}
+	return out
+}
+
+func parseQValue(params string) (float64, bool) {
+	params = strings.TrimSpace(params)
+	if !strings.HasPrefix(params, "q=") {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// rejectsEverything reports whether the client's Accept-Encoding header
+// explicitly forbids the identity encoding along with every wildcard,
+// per RFC 7231 ("identity;q=0, *;q=0" means nothing is acceptable).
+func rejectsEverything(accepted []acceptedEncoding) bool {
+	if len(accepted) == 0 {
+		return false
+	}
+
+	var identityForbidden, wildcardForbidden, sawWildcard bool
+	for _, a := range accepted {
+		switch a.name {
+		case "identity":
+			identityForbidden = a.q == 0
+		case "*":
+			sawWildcard = true
+			wildcardForbidden = a.q == 0
+		}
+	}
+	return identityForbidden && sawWildcard && wildcardForbidden
+}
+
+// negotiate picks the best encoding present in both accepted and
+// supported, preferring higher q-values and, on ties, supported's order.
+func negotiate(accepted []acceptedEncoding, supported []string) string {
+	if len(accepted) == 0 {
+		// No Accept-Encoding header: identity is acceptable by default,
+		// so don't compress.
+		return ""
+	}
+
+	qFor := func(name string) (float64, bool) {
+		var wildcard float64 = -1
+		for _, a := range accepted {
+			if a.name == name {
+				return a.q, true
+			}
+			if a.name == "*" {
+				wildcard = a.q
+			}
+		}
+		if wildcard >= 0 {
+			return wildcard, true
+		}
+		return 0, false
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range supported {
+		q, ok := qFor(enc)
+		if !ok || q <= 0 {
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// encoderPools keeps one sync.Pool per encoding, each seeded with
+// writers built at a fixed compression level, so reused writers always
+// match the level New(cfg) was configured with.
+type encoderPools struct {
+	gzip   sync.Pool
+	flate  sync.Pool
+	brotli sync.Pool
+}
+
+func newEncoderPools(level int) *encoderPools {
+	gzipLevel := level
+	if gzipLevel == 0 {
+		gzipLevel = gzip.DefaultCompression
+	}
+	flateLevel := level
+	if flateLevel == 0 {
+		flateLevel = flate.DefaultCompression
+	}
+	brotliLevel := level
+	if brotliLevel == 0 {
+		brotliLevel = brotli.DefaultCompression
+	}
+
+	return &encoderPools{
+		gzip: sync.Pool{New: func() interface{} {
+			w, _ := gzip.NewWriterLevel(io.Discard, gzipLevel)
+			return w
+		}},
+		flate: sync.Pool{New: func() interface{} {
+			w, _ := flate.NewWriter(io.Discard, flateLevel)
+			return w
+		}},
+		brotli: sync.Pool{New: func() interface{} {
+			return brotli.NewWriterLevel(io.Discard, brotliLevel)
+		}},
+	}
+}
+
+func (p *encoderPools) acquire(encoding string, w io.Writer) io.WriteCloser {
+	switch encoding {
+	case "gzip":
+		gw := p.gzip.Get().(*gzip.Writer)
+		gw.Reset(w)
+		return gw
+	case "deflate":
+		fw := p.flate.Get().(*flate.Writer)
+		fw.Reset(w)
+		return fw
+	case "br":
+		bw := p.brotli.Get().(*brotli.Writer)
+		bw.Reset(w)
+		return bw
+	default:
+		return nopWriteCloser{w}
+	}
+}
+
+func (p *encoderPools) release(encoding string, enc io.WriteCloser) {
+	switch encoding {
+	case "gzip":
+		p.gzip.Put(enc)
+	case "deflate":
+		p.flate.Put(enc)
+	case "br":
+		p.brotli.Put(enc)
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,153 @@
+package compress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func handlerWithBody(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+// TestCompress_ChoosesEncoding verifies that the middleware picks the
+// expected Content-Encoding for a few representative Accept-Encoding
+// headers.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestCompress_ChoosesEncoding
+func TestCompress_ChoosesEncoding(t *testing.T) {
+	body := strings.Repeat("a", 2048)
+
+	cases := []struct {
+		name           string
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{"gzip only", "gzip", "gzip"},
+		{"deflate only", "deflate", "deflate"},
+		{"brotli preferred", "gzip, br", "br"},
+		{"q-values", "gzip;q=0.2, deflate;q=0.8", "deflate"},
+		{"no header means no compression", "", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mw := New(Config{MinLength: 1})
+			handler := mw(handlerWithBody(body))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			got := w.Header().Get("Content-Encoding")
+			if got != tc.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tc.wantEncoding)
+			}
+		})
+	}
+}
+
+// TestCompress_SkipsSmallBodies verifies that bodies smaller than
+// MinLength are left uncompressed.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestCompress_SkipsSmallBodies
+func TestCompress_SkipsSmallBodies(t *testing.T) {
+	mw := New(Config{MinLength: 1024})
+	handler := mw(handlerWithBody("short"))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if enc := w.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("expected no Content-Encoding for a short body, got %q", enc)
+	}
+	if w.Body.String() != "short" {
+		t.Errorf("expected body to pass through unchanged, got %q", w.Body.String())
+	}
+}
+
+// TestCompress_NotAcceptable verifies that a client rejecting every
+// encoding gets a 406 instead of a silently uncompressed body.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestCompress_NotAcceptable
+func TestCompress_NotAcceptable(t *testing.T) {
+	mw := New(Config{MinLength: 1})
+	handler := mw(handlerWithBody("hello"))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "identity;q=0, *;q=0")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNotAcceptable {
+		t.Errorf("expected status 406, got %d", w.Code)
+	}
+}
+
+// TestCompress_SkipsImageContentType verifies that already-compressed
+// content types are left alone even when large enough to qualify.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestCompress_SkipsImageContentType
+func TestCompress_SkipsImageContentType(t *testing.T) {
+	mw := New(Config{MinLength: 1})
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte(strings.Repeat("x", 2048)))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if enc := w.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("expected no Content-Encoding for image/png, got %q", enc)
+	}
+}
+
+// TestCompress_SkipsPartialContent verifies that a 206 Partial Content
+// response, as produced by a Range request, is never compressed —
+// compressing a byte range would invalidate the Content-Range offsets
+// the client asked for.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestCompress_SkipsPartialContent
+func TestCompress_SkipsPartialContent(t *testing.T) {
+	mw := New(Config{MinLength: 1})
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", "bytes 0-4/10")
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write([]byte(strings.Repeat("x", 2048)))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	r.Header.Set("Range", "bytes=0-4")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if enc := w.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("expected no Content-Encoding for a 206 response, got %q", enc)
+	}
+	if w.Code != http.StatusPartialContent {
+		t.Errorf("expected status 206, got %d", w.Code)
+	}
+}
@@ -0,0 +1,162 @@
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Policy describes a CORS policy that can be attached to a single route
+// or an entire group, as opposed to cors.New()'s app-wide configuration.
+type Policy struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+
+	// MaxAge is the number of seconds browsers may cache a preflight
+	// response for. Zero omits Access-Control-Max-Age entirely.
+	MaxAge int
+}
+
+func (p Policy) allowsOrigin(origin string) bool {
+	for _, o := range p.AllowOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Policy) allowsMethod(method string) bool {
+	for _, m := range p.AllowMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Policy) allowsHeaders(requested string) bool {
+	if requested == "" {
+		return true
+	}
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.TrimSpace(h)
+		ok := false
+		for _, allowed := range p.AllowHeaders {
+			if strings.EqualFold(allowed, h) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func addVary(h http.Header, values ...string) {
+	existing := h.Values("Vary")
+	for _, v := range values {
+		found := false
+		for _, e := range existing {
+			if strings.EqualFold(strings.TrimSpace(e), v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			h.Add("Vary", v)
+		}
+	}
+}
+
+// Middleware wraps next, answering preflight requests directly and
+// annotating simple/actual requests that pass through to next,
+// according to p.
+func (p Policy) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !p.allowsOrigin(origin) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				p.writePreflight(w, r, origin)
+				return
+			}
+
+			p.writeSimpleHeaders(w, origin)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Preflight returns a standalone handler that answers OPTIONS preflight
+// requests against p. It's meant for registering a dedicated OPTIONS
+// route for a single endpoint, as a lighter alternative to wrapping the
+// whole route tree in Middleware.
+func Preflight(p Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !p.allowsOrigin(origin) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		p.writePreflight(w, r, origin)
+	}
+}
+
+func (p Policy) writeSimpleHeaders(w http.ResponseWriter, origin string) {
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", origin)
+	addVary(h, "Origin")
+	if p.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(p.ExposeHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(p.ExposeHeaders, ", "))
+	}
+}
+
+func (p Policy) writePreflight(w http.ResponseWriter, r *http.Request, origin string) {
+	reqMethod := r.Header.Get("Access-Control-Request-Method")
+	reqHeaders := r.Header.Get("Access-Control-Request-Headers")
+
+	h := w.Header()
+	addVary(h, "Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers")
+
+	if !p.allowsMethod(reqMethod) || !p.allowsHeaders(reqHeaders) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	h.Set("Access-Control-Allow-Origin", origin)
+	h.Set("Access-Control-Allow-Methods", strings.Join(p.AllowMethods, ", "))
+	if reqHeaders != "" {
+		h.Set("Access-Control-Allow-Headers", reqHeaders)
+	} else if len(p.AllowHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(p.AllowHeaders, ", "))
+	}
+	if p.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(p.ExposeHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(p.ExposeHeaders, ", "))
+	}
+	if p.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(p.MaxAge))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,259 @@
+package session
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrInvalidCookie is returned when a session cookie fails to verify or
+// decode, either because it was tampered with or has expired.
+var ErrInvalidCookie = errors.New("session: invalid or expired cookie")
+
+// CookieStore stores the whole session inside the cookie itself,
+// authenticated with HMAC-SHA256 and, when a block key is supplied,
+// encrypted with AES-GCM — the same securecookie scheme used across the
+// Go ecosystem.
+type CookieStore struct {
+	// Options is used as the default for every session created by this
+	// store; a session's own Options may be changed before Save.
+	Options *Options
+
+	hashKey  []byte
+	blockKey []byte
+}
+
+// NewCookieStore creates a CookieStore from alternating hash/block key
+// pairs: keyPairs[0] is the HMAC signing key (required), keyPairs[1] is an
+// AES key used to encrypt the payload (optional, 16/24/32 bytes). Extra
+// pairs beyond the first are accepted for future key rotation but only
+// the first pair is currently used.
+func NewCookieStore(keyPairs ...[]byte) *CookieStore {
+	cs := &CookieStore{
+		Options: &Options{
+			Path:     "/",
+			MaxAge:   DefaultMaxAge,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+	}
+	if len(keyPairs) > 0 {
+		cs.hashKey = keyPairs[0]
+	}
+	if len(keyPairs) > 1 {
+		cs.blockKey = keyPairs[1]
+	}
+	return cs
+}
+
+func (cs *CookieStore) New(r *http.Request, name string) (*Session, error) {
+	opts := *cs.Options
+	return &Session{
+		name:    name,
+		store:   cs,
+		request: r,
+		isNew:   true,
+		Values:  make(map[string]interface{}),
+		Options: &opts,
+	}, nil
+}
+
+func (cs *CookieStore) Get(r *http.Request, name string) (*Session, error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return cs.New(r, name)
+	}
+
+	payload, err := cs.decode(c.Value)
+	if err != nil {
+		return cs.New(r, name)
+	}
+
+	opts := *cs.Options
+	return &Session{
+		name:      name,
+		store:     cs,
+		request:   r,
+		Values:    payload.Values,
+		Options:   &opts,
+		expiresAt: payload.ExpiresAt,
+	}, nil
+}
+
+func (cs *CookieStore) Save(w http.ResponseWriter, s *Session) error {
+	opts := s.Options
+	if opts == nil {
+		opts = cs.Options
+	}
+
+	if opts.MaxAge < 0 {
+		http.SetCookie(w, newCookie(s.name, "", opts))
+		return nil
+	}
+
+	maxAge := opts.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	expiresAt := s.expiresAt
+	if opts.Rolling || expiresAt.IsZero() {
+		expiresAt = time.Now().Add(time.Duration(maxAge) * time.Second)
+	}
+	s.expiresAt = expiresAt
+
+	encoded, err := cs.encode(cookiePayload{Values: s.Values, ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+
+	cookie := newCookie(s.name, encoded, opts)
+	cookie.Expires = expiresAt
+	cookie.MaxAge = int(time.Until(expiresAt).Seconds())
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+func newCookie(name, value string, opts *Options) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     opts.Path,
+		Domain:   opts.Domain,
+		MaxAge:   opts.MaxAge,
+		Secure:   opts.Secure,
+		HttpOnly: opts.HttpOnly,
+		SameSite: opts.SameSite,
+	}
+}
+
+// cookiePayload is what actually gets signed/encrypted into the cookie.
+// ExpiresAt travels alongside Values so a non-Rolling session's Save can
+// recover the expiration it was first created with, since nothing about
+// that is preserved by the browser's Cookie header on its own.
+type cookiePayload struct {
+	Values    map[string]interface{}
+	ExpiresAt time.Time
+}
+
+// encode signs (and, when a block key is present, encrypts) payload and
+// returns a cookie-safe base64 string.
+func (cs *CookieStore) encode(payload cookiePayload) (string, error) {
+	plain, err := gobEncode(payload)
+	if err != nil {
+		return "", err
+	}
+
+	if len(cs.blockKey) > 0 {
+		plain, err = encrypt(cs.blockKey, plain)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	mac := macFor(cs.hashKey, plain)
+	signed := append(plain, mac...)
+	return base64.URLEncoding.EncodeToString(signed), nil
+}
+
+// decode reverses encode, rejecting the payload if the MAC does not
+// match, decryption fails, or its expiration has passed.
+func (cs *CookieStore) decode(raw string) (cookiePayload, error) {
+	signed, err := base64.URLEncoding.DecodeString(raw)
+	if err != nil {
+		return cookiePayload{}, ErrInvalidCookie
+	}
+
+	macSize := sha256.Size
+	if len(signed) < macSize {
+		return cookiePayload{}, ErrInvalidCookie
+	}
+	plain, mac := signed[:len(signed)-macSize], signed[len(signed)-macSize:]
+
+	if !hmac.Equal(mac, macFor(cs.hashKey, plain)) {
+		return cookiePayload{}, ErrInvalidCookie
+	}
+
+	if len(cs.blockKey) > 0 {
+		plain, err = decrypt(cs.blockKey, plain)
+		if err != nil {
+			return cookiePayload{}, ErrInvalidCookie
+		}
+	}
+
+	var payload cookiePayload
+	if err := gobDecode(plain, &payload); err != nil {
+		return cookiePayload{}, ErrInvalidCookie
+	}
+	if !payload.ExpiresAt.IsZero() && time.Now().After(payload.ExpiresAt) {
+		return cookiePayload{}, ErrInvalidCookie
+	}
+	return payload, nil
+}
+
+func macFor(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func encrypt(key, plain []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plain, nil), nil
+}
+
+func decrypt(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrInvalidCookie
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// gobEncode/gobDecode encode v directly with gob, rather than round
+// tripping it through JSON first: json.Unmarshal into an interface{}
+// always produces float64 for numbers, so a session value Set as an int
+// would silently come back as a float64 after one Save/Get cycle. gob
+// preserves concrete types for the built-ins (int, bool, string, and so
+// on) without help, but a custom struct type stored in a session's
+// Values still needs gob.Register by the caller, the same requirement
+// any other gob-based session store carries.
+func gobEncode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobDecode(data []byte, out interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(out)
+}
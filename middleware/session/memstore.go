@@ -0,0 +1,167 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type memEntry struct {
+	values map[string]interface{}
+	expiry time.Time
+}
+
+// MemStore keeps every session in process memory, identified by an opaque
+// ID stored in the session cookie. It is intended for single-instance
+// deployments, local development, and tests; use CookieStore or a
+// custom Store for anything that needs to survive a restart or run
+// behind more than one instance.
+type MemStore struct {
+	Options *Options
+
+	data    sync.Map // sessionID(string) -> *memEntry
+	janitor *time.Ticker
+	done    chan struct{}
+}
+
+// NewMemStore creates a MemStore and starts its background janitor,
+// which sweeps expired sessions every interval. Call Close (typically
+// from Quick's shutdown path) to stop the janitor goroutine.
+func NewMemStore() *MemStore {
+	ms := &MemStore{
+		Options: &Options{
+			Path:     "/",
+			MaxAge:   DefaultMaxAge,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+		janitor: time.NewTicker(time.Minute),
+		done:    make(chan struct{}),
+	}
+	go ms.runJanitor()
+	return ms
+}
+
+func (ms *MemStore) runJanitor() {
+	for {
+		select {
+		case <-ms.janitor.C:
+			ms.sweep()
+		case <-ms.done:
+			return
+		}
+	}
+}
+
+func (ms *MemStore) sweep() {
+	now := time.Now()
+	ms.data.Range(func(key, value interface{}) bool {
+		entry := value.(*memEntry)
+		if now.After(entry.expiry) {
+			ms.data.Delete(key)
+		}
+		return true
+	})
+}
+
+// Close stops the janitor goroutine. It is safe to call more than once.
+func (ms *MemStore) Close() error {
+	select {
+	case <-ms.done:
+	default:
+		close(ms.done)
+		ms.janitor.Stop()
+	}
+	return nil
+}
+
+func (ms *MemStore) New(r *http.Request, name string) (*Session, error) {
+	opts := *ms.Options
+	return &Session{
+		name:    name,
+		store:   ms,
+		request: r,
+		isNew:   true,
+		id:      newSessionID(),
+		Values:  make(map[string]interface{}),
+		Options: &opts,
+	}, nil
+}
+
+func (ms *MemStore) Get(r *http.Request, name string) (*Session, error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return ms.New(r, name)
+	}
+
+	raw, ok := ms.data.Load(c.Value)
+	if !ok {
+		return ms.New(r, name)
+	}
+
+	entry := raw.(*memEntry)
+	if time.Now().After(entry.expiry) {
+		ms.data.Delete(c.Value)
+		return ms.New(r, name)
+	}
+
+	opts := *ms.Options
+	return &Session{
+		name:      name,
+		store:     ms,
+		request:   r,
+		id:        c.Value,
+		Values:    entry.values,
+		Options:   &opts,
+		expiresAt: entry.expiry,
+	}, nil
+}
+
+func (ms *MemStore) Save(w http.ResponseWriter, s *Session) error {
+	opts := s.Options
+	if opts == nil {
+		opts = ms.Options
+	}
+
+	if opts.MaxAge < 0 {
+		ms.data.Delete(s.id)
+		http.SetCookie(w, newCookie(s.name, "", opts))
+		return nil
+	}
+
+	if s.id == "" {
+		s.id = newSessionID()
+	}
+
+	maxAge := opts.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	expiry := s.expiresAt
+	if opts.Rolling || expiry.IsZero() {
+		expiry = time.Now().Add(time.Duration(maxAge) * time.Second)
+	}
+	s.expiresAt = expiry
+
+	ms.data.Store(s.id, &memEntry{
+		values: s.Values,
+		expiry: expiry,
+	})
+
+	cookie := newCookie(s.name, s.id, opts)
+	cookie.Expires = expiry
+	cookie.MaxAge = int(time.Until(expiry).Seconds())
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+func newSessionID() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
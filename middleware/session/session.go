@@ -0,0 +1,224 @@
+// Package session provides pluggable HTTP session support for Quick.
+//
+// A session is backed by a Store implementation (see NewCookieStore and
+// NewMemStore) and exposed to handlers through Ctx.Session(). Values are
+// only decoded from the incoming request the first time Session() is
+// called, so routes that never touch the session pay no decoding cost.
+package session
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultMaxAge is used when an Options value does not set MaxAge.
+const DefaultMaxAge = 86400 * 30 // 30 days
+
+// Options configures cookie attributes and expiration behavior for a
+// session. It mirrors the fields of http.Cookie that matter for sessions
+// plus a Rolling flag to refresh the expiration on every request.
+type Options struct {
+	Path     string
+	Domain   string
+	MaxAge   int
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+
+	// Rolling extends the session's expiration on every request that
+	// touches it, instead of only at creation time.
+	Rolling bool
+}
+
+// Session holds the data for a single named session plus the Options
+// that will be used when it is written back.
+type Session struct {
+	name    string
+	store   Store
+	request *http.Request
+
+	isNew   bool
+	id      string
+	Values  map[string]interface{}
+	Options *Options
+
+	// expiresAt is the expiration the session was last saved with. A
+	// store's Save reuses it instead of recomputing from time.Now() when
+	// Options.Rolling is false, so a fixed-lifetime session actually
+	// expires on schedule instead of refreshing on every request.
+	expiresAt time.Time
+
+	mu sync.Mutex
+}
+
+// Store is implemented by session backends. Get must return a new, empty
+// session when no valid session is present on the request (the stores in
+// this package return a nil error in that case); it must never return a
+// nil *Session together with a nil error.
+type Store interface {
+	// Get returns the session named name for the given request, or a new
+	// empty session if none exists yet or it failed to decode.
+	Get(r *http.Request, name string) (*Session, error)
+
+	// New always returns a new, empty session named name.
+	New(r *http.Request, name string) (*Session, error)
+
+	// Save persists s and writes whatever cookie is needed onto w.
+	Save(w http.ResponseWriter, s *Session) error
+}
+
+// Get returns the value stored under key, or nil if it is not present.
+func (s *Session) Get(key string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Values[key]
+}
+
+// Set stores value under key.
+func (s *Session) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Values == nil {
+		s.Values = make(map[string]interface{})
+	}
+	s.Values[key] = value
+}
+
+// Delete removes key from the session.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.Values, key)
+}
+
+// Clear removes every value from the session.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Values = make(map[string]interface{})
+}
+
+// IsNew reports whether the session was just created, i.e. there was no
+// valid session on the incoming request.
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// Save writes the session back to its Store, which for cookie-backed
+// sessions sets the Set-Cookie header on w and for server-side stores
+// persists the data and refreshes the session cookie.
+func (s *Session) Save(w http.ResponseWriter) error {
+	return s.store.Save(w, s)
+}
+
+const flashKey = "_flash"
+
+// Flashes returns and clears the flash messages queued on the session.
+func (s *Session) Flashes() []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, ok := s.Values[flashKey]
+	if !ok {
+		return nil
+	}
+	delete(s.Values, flashKey)
+
+	flashes, _ := raw.([]interface{})
+	return flashes
+}
+
+// AddFlash queues value to be returned by the next call to Flashes.
+func (s *Session) AddFlash(value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Values == nil {
+		s.Values = make(map[string]interface{})
+	}
+	flashes, _ := s.Values[flashKey].([]interface{})
+	s.Values[flashKey] = append(flashes, value)
+}
+
+type ctxKey struct{ name string }
+
+// loader lazily fetches a named session from its store the first time it
+// is asked for, so handlers that never call Ctx.Session() never pay the
+// cost of decoding a cookie or hitting the store.
+type loader struct {
+	name  string
+	store Store
+
+	once    sync.Once
+	session *Session
+	err     error
+}
+
+func (l *loader) get(r *http.Request) (*Session, error) {
+	l.once.Do(func() {
+		l.session, l.err = l.store.Get(r, l.name)
+	})
+	return l.session, l.err
+}
+
+// Sessions wraps next so every request carries a session named name,
+// available to handlers via Ctx.Session(). Whatever the handler did to
+// the session is saved back to store right before the response headers
+// are written, regardless of which handler produced the response.
+func Sessions(name string, store Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l := &loader{name: name, store: store}
+			ctx := context.WithValue(r.Context(), ctxKey{name}, l)
+			r = r.WithContext(ctx)
+
+			sw := &saveWriter{ResponseWriter: w, loader: l, request: r}
+			next.ServeHTTP(sw, r)
+			sw.flush()
+		})
+	}
+}
+
+// saveWriter defers saving the loaded session until the first time the
+// response is about to be written, so Save happens before headers flush
+// regardless of how the handler produced its response.
+type saveWriter struct {
+	http.ResponseWriter
+	loader  *loader
+	request *http.Request
+	saved   bool
+}
+
+func (w *saveWriter) flush() {
+	if w.saved {
+		return
+	}
+	w.saved = true
+	if w.loader.session == nil {
+		return
+	}
+	_ = w.loader.session.Save(w.ResponseWriter)
+}
+
+func (w *saveWriter) WriteHeader(code int) {
+	w.flush()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *saveWriter) Write(b []byte) (int, error) {
+	w.flush()
+	return w.ResponseWriter.Write(b)
+}
+
+// FromRequest returns the named session for r, loading it from its store
+// on first access. It is used by Ctx.Session() and is exported so other
+// integrations can fetch a session without going through Ctx.
+func FromRequest(r *http.Request, name string) (*Session, error) {
+	l, ok := r.Context().Value(ctxKey{name}).(*loader)
+	if !ok {
+		return nil, http.ErrNoCookie
+	}
+	return l.get(r)
+}
@@ -0,0 +1,282 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMemStore_SetGet verifies that a value saved through a MemStore
+// session is readable back from a subsequent request carrying the
+// resulting cookie.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestMemStore_SetGet
+func TestMemStore_SetGet(t *testing.T) {
+	store := NewMemStore()
+	defer store.Close()
+
+	r1 := httptest.NewRequest(http.MethodGet, "/", nil)
+	w1 := httptest.NewRecorder()
+
+	sess, err := store.Get(r1, "session")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !sess.IsNew() {
+		t.Errorf("expected a new session on first request")
+	}
+	sess.Set("user", "jeff")
+
+	if err := sess.Save(w1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	resp := w1.Result()
+	cookies := resp.Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected 1 cookie, got %d", len(cookies))
+	}
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.AddCookie(cookies[0])
+
+	sess2, err := store.Get(r2, "session")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if sess2.IsNew() {
+		t.Errorf("expected an existing session on second request")
+	}
+	if got := sess2.Get("user"); got != "jeff" {
+		t.Errorf("expected user 'jeff', got %v", got)
+	}
+}
+
+// TestCookieStore_SetGet verifies that values survive a full
+// encode/decode round trip through a signed cookie.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestCookieStore_SetGet
+func TestCookieStore_SetGet(t *testing.T) {
+	store := NewCookieStore([]byte("0123456789abcdef0123456789abcdef"))
+
+	r1 := httptest.NewRequest(http.MethodGet, "/", nil)
+	w1 := httptest.NewRecorder()
+
+	sess, _ := store.Get(r1, "session")
+	sess.Set("role", "admin")
+	if err := sess.Save(w1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	cookies := w1.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected 1 cookie, got %d", len(cookies))
+	}
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.AddCookie(cookies[0])
+
+	sess2, err := store.Get(r2, "session")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := sess2.Get("role"); got != "admin" {
+		t.Errorf("expected role 'admin', got %v", got)
+	}
+}
+
+// TestCookieStore_TamperedCookie verifies that a modified cookie value
+// is rejected instead of being decoded into a forged session.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestCookieStore_TamperedCookie
+func TestCookieStore_TamperedCookie(t *testing.T) {
+	store := NewCookieStore([]byte("0123456789abcdef0123456789abcdef"))
+
+	r1 := httptest.NewRequest(http.MethodGet, "/", nil)
+	w1 := httptest.NewRecorder()
+
+	sess, _ := store.Get(r1, "session")
+	sess.Set("role", "admin")
+	_ = sess.Save(w1)
+
+	cookies := w1.Result().Cookies()
+	cookies[0].Value = cookies[0].Value + "tampered"
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.AddCookie(cookies[0])
+
+	sess2, err := store.Get(r2, "session")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !sess2.IsNew() {
+		t.Errorf("expected a tampered cookie to fall back to a new session")
+	}
+}
+
+// TestMemStore_NonRollingKeepsOriginalExpiry verifies that a session
+// saved twice with Rolling false keeps the expiration it was first
+// created with instead of extending it on the second Save.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestMemStore_NonRollingKeepsOriginalExpiry
+func TestMemStore_NonRollingKeepsOriginalExpiry(t *testing.T) {
+	store := NewMemStore()
+	defer store.Close()
+	store.Options.MaxAge = 60
+	store.Options.Rolling = false
+
+	r1 := httptest.NewRequest(http.MethodGet, "/", nil)
+	w1 := httptest.NewRecorder()
+
+	sess, err := store.Get(r1, "session")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	sess.Set("user", "jeff")
+	if err := sess.Save(w1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	firstExpiry := sess.expiresAt
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.AddCookie(w1.Result().Cookies()[0])
+	w2 := httptest.NewRecorder()
+
+	sess2, err := store.Get(r2, "session")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	sess2.Set("user", "jeff2")
+	if err := sess2.Save(w2); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if !sess2.expiresAt.Equal(firstExpiry) {
+		t.Errorf("expected expiry to stay at %v, got %v", firstExpiry, sess2.expiresAt)
+	}
+}
+
+// TestCookieStore_RollingExtendsExpiry verifies that a session saved
+// twice with Rolling true gets a later expiration on the second Save.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestCookieStore_RollingExtendsExpiry
+func TestCookieStore_RollingExtendsExpiry(t *testing.T) {
+	store := NewCookieStore([]byte("0123456789abcdef0123456789abcdef"))
+	store.Options.MaxAge = 60
+	store.Options.Rolling = true
+
+	r1 := httptest.NewRequest(http.MethodGet, "/", nil)
+	w1 := httptest.NewRecorder()
+
+	sess, _ := store.Get(r1, "session")
+	sess.Set("role", "admin")
+	if err := sess.Save(w1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	firstExpiry := sess.expiresAt
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.AddCookie(w1.Result().Cookies()[0])
+	w2 := httptest.NewRecorder()
+
+	sess2, err := store.Get(r2, "session")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := sess2.Save(w2); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if !sess2.expiresAt.After(firstExpiry) {
+		t.Errorf("expected rolling save to push expiry past %v, got %v", firstExpiry, sess2.expiresAt)
+	}
+}
+
+// TestCookieStore_PreservesValueTypes verifies that int and bool values
+// come back with their original concrete type after a CookieStore
+// Save/Get round trip, rather than being widened to float64 the way a
+// JSON round trip through interface{} would.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestCookieStore_PreservesValueTypes
+func TestCookieStore_PreservesValueTypes(t *testing.T) {
+	store := NewCookieStore([]byte("0123456789abcdef0123456789abcdef"))
+
+	r1 := httptest.NewRequest(http.MethodGet, "/", nil)
+	w1 := httptest.NewRecorder()
+
+	sess, _ := store.Get(r1, "session")
+	sess.Set("visits", 3)
+	sess.Set("active", true)
+	if err := sess.Save(w1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.AddCookie(w1.Result().Cookies()[0])
+
+	sess2, err := store.Get(r2, "session")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	visits, ok := sess2.Get("visits").(int)
+	if !ok {
+		t.Fatalf("expected visits to come back as int, got %T", sess2.Get("visits"))
+	}
+	if visits != 3 {
+		t.Errorf("expected visits 3, got %d", visits)
+	}
+
+	active, ok := sess2.Get("active").(bool)
+	if !ok {
+		t.Fatalf("expected active to come back as bool, got %T", sess2.Get("active"))
+	}
+	if !active {
+		t.Errorf("expected active true, got %v", active)
+	}
+}
+
+// TestSessions_Middleware verifies that the Sessions middleware makes a
+// session available to the wrapped handler and saves it before the
+// response is written.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestSessions_Middleware
+func TestSessions_Middleware(t *testing.T) {
+	store := NewMemStore()
+	defer store.Close()
+
+	mw := Sessions("session", store)
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess, err := FromRequest(r, "session")
+		if err != nil {
+			t.Fatalf("FromRequest: %v", err)
+		}
+		sess.Set("visits", 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+	if len(rec.Result().Cookies()) != 1 {
+		t.Errorf("expected the session to be saved as a cookie")
+	}
+}
@@ -0,0 +1,52 @@
+package quick
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mount copies every route registered on other onto q, rewriting each
+// route's pattern to live under prefix. It's meant for composing several
+// Quick apps into a single binary, e.g. mounting an admin app under
+// "/admin" next to a public app under "/":
+//
+//	q.Mount("/admin", adminApp)
+//
+// Since each route's handler is whatever other composed at Get/Post/...
+// time, per-route and per-group middleware registered on other travels
+// along with it automatically. Middleware attached to other itself via
+// Use is applied at its own dispatch level and is not carried over;
+// call q.Use with the same middleware if the mounted app needs it too.
+//
+// GetRoute() only reports Pattern reliably for GET routes (see
+// ExampleQuick_GetRoute); a route whose Pattern comes back empty can't
+// be rewritten under prefix without silently colliding with every other
+// such route at the bare prefix, so Mount refuses to mount it instead.
+func (q *Quick) Mount(prefix string, other *Quick) error {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	for _, route := range other.GetRoute() {
+		if route.Pattern == "" {
+			return fmt.Errorf("quick: mount %s: route %s has no reported pattern", prefix, route.Method)
+		}
+		pattern := prefix + route.Pattern
+		switch route.Method {
+		case MethodGet:
+			q.Get(pattern, route.Handler)
+		case MethodPost:
+			q.Post(pattern, route.Handler)
+		case MethodPut:
+			q.Put(pattern, route.Handler)
+		case MethodDelete:
+			q.Delete(pattern, route.Handler)
+		case MethodPatch:
+			q.Patch(pattern, route.Handler)
+		case MethodOptions:
+			q.Options(pattern, route.Handler)
+		default:
+			return fmt.Errorf("quick: mount %s: unsupported method %q", pattern, route.Method)
+		}
+	}
+
+	return nil
+}
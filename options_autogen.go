@@ -0,0 +1,67 @@
+package quick
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// AutoOptions synthesizes an OPTIONS handler for every pattern that has
+// at least one route registered but no explicit OPTIONS handler of its
+// own, answering with the pattern's Allow header and a 204. Call it once
+// after every other route has been registered, e.g. right before
+// q.Listen. A pattern whose OPTIONS route GetRoute() reports with its
+// Pattern is left untouched; an OPTIONS route (from RouteCORS,
+// Quick.CORS, or a manual q.Options call) reported with an empty
+// Pattern makes AutoOptions return an error, as described below.
+//
+// GetRoute() only reports Pattern reliably for GET routes (see
+// ExampleQuick_GetRoute). Grouping a route with an empty Pattern under
+// its method's siblings would mean either dropping it from the Allow
+// header silently or merging it into the wrong pattern's group, so
+// AutoOptions returns an error for it instead of guessing.
+func (q *Quick) AutoOptions() error {
+	methodsByPattern := make(map[string]map[string]bool)
+	hasOptions := make(map[string]bool)
+
+	for _, route := range q.GetRoute() {
+		if route.Pattern == "" {
+			return fmt.Errorf("quick: AutoOptions: route %s has no reported pattern", route.Method)
+		}
+		if route.Method == MethodOptions {
+			hasOptions[route.Pattern] = true
+			continue
+		}
+		if methodsByPattern[route.Pattern] == nil {
+			methodsByPattern[route.Pattern] = make(map[string]bool)
+		}
+		methodsByPattern[route.Pattern][route.Method] = true
+	}
+
+	for pattern, methods := range methodsByPattern {
+		if hasOptions[pattern] {
+			continue
+		}
+		q.Options(pattern, allowHandler(allowHeader(methods)))
+	}
+	return nil
+}
+
+func allowHeader(methods map[string]bool) string {
+	methods[MethodOptions] = true
+
+	list := make([]string, 0, len(methods))
+	for m := range methods {
+		list = append(list, m)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
+func allowHandler(allow string) func(c *Ctx) error {
+	return func(c *Ctx) error {
+		c.Set("Allow", allow)
+		return c.Status(http.StatusNoContent).Send(nil)
+	}
+}
@@ -0,0 +1,69 @@
+package quick
+
+import (
+	"sort"
+	"strings"
+)
+
+// Redirect registers a route that answers from with an HTTP redirect to
+// to, using status as the redirect status code (301, 302, 307, 308). It
+// saves handwriting a handler for the common case of moving a route:
+//
+//	q.Redirect(301, "/foo", "/bar")
+//
+// to may reuse any :name path parameter declared in from, so template
+// style redirects like Redirect(308, "/v1/users/:id", "/v2/users/:id")
+// forward the matched segment.
+//
+// The redirect answers GET, POST, PUT, DELETE and PATCH, not only GET
+// (OPTIONS is not registered): 307 and 308 exist specifically to
+// preserve the original method across a redirect, so a POST to a moved
+// route needs to land on the same redirect a GET would.
+func (q *Quick) Redirect(status int, from, to string) {
+	handler := redirectHandler(status, to)
+	q.Get(from, handler)
+	q.Post(from, handler)
+	q.Put(from, handler)
+	q.Delete(from, handler)
+	q.Patch(from, handler)
+}
+
+// Redirect registers a redirect scoped to the group's prefix, with the
+// same semantics as Quick.Redirect.
+func (g *Group) Redirect(status int, from, to string) {
+	handler := redirectHandler(status, to)
+	g.Get(from, handler)
+	g.Post(from, handler)
+	g.Put(from, handler)
+	g.Delete(from, handler)
+	g.Patch(from, handler)
+}
+
+func redirectHandler(status int, to string) func(c *Ctx) error {
+	return func(c *Ctx) error {
+		c.Set("Location", expandParams(to, c.Params))
+		return c.Status(status).Send(nil)
+	}
+}
+
+// expandParams replaces every :name token in pattern with the matching
+// value from params, leaving unmatched tokens untouched. Names are
+// substituted longest-first so that one param name that's a prefix of
+// another (:id vs :identity) can't have its replacement clobber part of
+// the other's token before that token gets its turn.
+func expandParams(pattern string, params map[string]string) string {
+	if len(params) == 0 {
+		return pattern
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	result := pattern
+	for _, name := range names {
+		result = strings.ReplaceAll(result, ":"+name, params[name])
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+package quick
+
+import "testing"
+
+// TestExpandParams verifies that :name tokens in a redirect target are
+// replaced with their matched path parameter values.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestExpandParams
+func TestExpandParams(t *testing.T) {
+	got := expandParams("/v2/users/:id", map[string]string{"id": "42"})
+	want := "/v2/users/42"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestExpandParams_PrefixCollision verifies that a param name which is a
+// prefix of another (:id vs :identity) doesn't corrupt the longer name's
+// token when both are present.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestExpandParams_PrefixCollision
+func TestExpandParams_PrefixCollision(t *testing.T) {
+	got := expandParams("/v2/:id/:identity", map[string]string{"id": "42", "identity": "alice"})
+	want := "/v2/42/alice"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestQuick_Redirect verifies that a registered redirect answers with
+// the configured status code and Location header.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_Redirect
+func TestQuick_Redirect(t *testing.T) {
+	q := New()
+	q.Redirect(301, "/old", "/new")
+
+	res, err := q.Qtest(QuickTestOptions{
+		Method: MethodGet,
+		URI:    "/old",
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+
+	if res.StatusCode() != 301 {
+		t.Errorf("expected status 301, but got %d", res.StatusCode())
+	}
+	if got := res.Response.Header.Get("Location"); got != "/new" {
+		t.Errorf("expected Location '/new', but got %q", got)
+	}
+}
+
+// TestQuick_Redirect_PreservesMethod verifies that a 308 redirect
+// registered with Quick.Redirect also answers non-GET methods, so a
+// POST to a moved route gets redirected instead of 404ing.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_Redirect_PreservesMethod
+func TestQuick_Redirect_PreservesMethod(t *testing.T) {
+	q := New()
+	q.Redirect(308, "/v1/users/:id", "/v2/users/:id")
+
+	res, err := q.Qtest(QuickTestOptions{
+		Method: MethodPost,
+		URI:    "/v1/users/42",
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+
+	if res.StatusCode() != 308 {
+		t.Errorf("expected status 308, but got %d", res.StatusCode())
+	}
+	if got := res.Response.Header.Get("Location"); got != "/v2/users/42" {
+		t.Errorf("expected Location '/v2/users/42', but got %q", got)
+	}
+}
+
+// TestQuick_Mount verifies that mounting a sub-app makes its routes
+// reachable under the given prefix.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_Mount
+func TestQuick_Mount(t *testing.T) {
+	admin := New()
+	admin.Get("/users", func(c *Ctx) error {
+		return c.Status(200).String("admin users")
+	})
+
+	q := New()
+	if err := q.Mount("/admin", admin); err != nil {
+		t.Fatalf("Mount: %v", err)
+	}
+
+	res, err := q.Qtest(QuickTestOptions{
+		Method: MethodGet,
+		URI:    "/admin/users",
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+
+	if res.StatusCode() != 200 {
+		t.Errorf("expected status 200, but got %d", res.StatusCode())
+	}
+	if res.BodyStr() != "admin users" {
+		t.Errorf("expected body 'admin users', but got %q", res.BodyStr())
+	}
+}
+
+// TestQuick_Mount_NonGETPattern verifies that Mount rejects a route it
+// can't safely rewrite under prefix, instead of silently collapsing it
+// onto the bare prefix when GetRoute() reports an empty Pattern for a
+// non-GET route (see ExampleQuick_GetRoute).
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_Mount_NonGETPattern
+func TestQuick_Mount_NonGETPattern(t *testing.T) {
+	admin := New()
+	admin.Post("/widgets", func(c *Ctx) error {
+		return c.Status(201).String("created")
+	})
+
+	routes := admin.GetRoute()
+	if len(routes) != 1 || routes[0].Pattern != "" {
+		t.Skipf("GetRoute() now reports Pattern for POST routes (got %q); Mount's guard is no longer exercised by this test", routes[0].Pattern)
+	}
+
+	q := New()
+	if err := q.Mount("/admin", admin); err == nil {
+		t.Errorf("expected Mount to reject a route with no reported pattern, got nil error")
+	}
+}
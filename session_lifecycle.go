@@ -0,0 +1,55 @@
+package quick
+
+import (
+	"io"
+	"sync"
+
+	"github.com/jeffotoni/quick/middleware/session"
+)
+
+// sessionClosers tracks, per *Quick instance, the stores registered
+// through UseSession that need to be closed on shutdown (e.g. a
+// MemStore's background janitor). It's a side table rather than a field
+// on Quick because Quick itself is defined outside this file and this
+// change doesn't touch it.
+var sessionClosers sync.Map // *Quick -> *[]io.Closer
+
+// UseSession installs the session middleware for store under name, the
+// same as calling q.Use(session.Sessions(name, store)) directly. When
+// store also implements io.Closer — as *session.MemStore does, to stop
+// its background janitor goroutine — UseSession registers it so
+// Quick.Shutdown can close it, instead of the goroutine leaking for the
+// life of the process.
+func (q *Quick) UseSession(name string, store session.Store) {
+	q.Use(session.Sessions(name, store))
+	if closer, ok := store.(io.Closer); ok {
+		value, _ := sessionClosers.LoadOrStore(q, &[]io.Closer{})
+		list := value.(*[]io.Closer)
+		*list = append(*list, closer)
+	}
+}
+
+// CloseSessionStores closes every store registered through UseSession
+// for q and forgets them, so a repeated call is a no-op.
+func CloseSessionStores(q *Quick) error {
+	value, ok := sessionClosers.LoadAndDelete(q)
+	if !ok {
+		return nil
+	}
+
+	var firstErr error
+	for _, closer := range *value.(*[]io.Closer) {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Shutdown stops any background work q owns outside the request/response
+// cycle itself — currently, any session store registered through
+// UseSession that needs closing, such as *session.MemStore's janitor
+// goroutine. It is safe to call more than once.
+func (q *Quick) Shutdown() error {
+	return CloseSessionStores(q)
+}
@@ -0,0 +1,27 @@
+package quick
+
+import (
+	"testing"
+
+	"github.com/jeffotoni/quick/middleware/session"
+)
+
+// TestQuick_UseSession_ClosesStoreOnShutdown verifies that a store
+// registered through UseSession gets closed by Quick.Shutdown, and that
+// shutting down twice is harmless.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_UseSession_ClosesStoreOnShutdown
+func TestQuick_UseSession_ClosesStoreOnShutdown(t *testing.T) {
+	q := New()
+	store := session.NewMemStore()
+	q.UseSession("session", store)
+
+	if err := q.Shutdown(); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := q.Shutdown(); err != nil {
+		t.Fatalf("Shutdown (second call): %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package quick
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StaticConfig customizes how Quick.Static serves a directory, in
+// particular what happens when a request resolves to a directory that
+// has no index.html. It works the same way over embed.FS, an
+// http.FileSystem, or a plain OS directory, since Static always talks to
+// its underlying filesystem through http.FileSystem.
+type StaticConfig struct {
+	// Browse enables a generated directory listing for directories that
+	// don't have an index.html. Off by default, in which case such a
+	// directory is handed to net/http.FileServer, which renders its own
+	// plain listing.
+	Browse bool
+
+	// Template overrides the listing's HTML template. It is executed
+	// with a listingPage value. When nil, a built-in template is used.
+	Template *template.Template
+
+	// IgnoreIndexes, when true, always renders the listing instead of
+	// serving a directory's index.html.
+	IgnoreIndexes bool
+
+	// SortBy sets the default sort column ("name", "size", or "time")
+	// used when the request doesn't supply a ?sort= query param.
+	SortBy string
+}
+
+// Static registers a route that serves files from root under prefix.
+// root may be an embed.FS, any other fs.FS, an http.FileSystem, or a
+// string naming a directory on disk. Without a StaticConfig, every
+// request, including one for a directory with no index.html, is served
+// by net/http.FileServer, which lists such a directory in its own plain
+// format. Passing a StaticConfig with Browse set renders a generated
+// listing (sortable, with JSON output) for such directories instead:
+//
+//	q.Static("/static", staticFiles, StaticConfig{Browse: true})
+func (q *Quick) Static(prefix string, root interface{}, config ...StaticConfig) {
+	var cfg StaticConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	fsys := toFileSystem(root)
+	prefix = strings.TrimSuffix(prefix, "/")
+	fileServer := http.FileServer(fsys)
+
+	q.Get(prefix+"/*", func(c *Ctx) error {
+		urlPath := strings.TrimPrefix(c.Request.URL.Path, prefix)
+		if urlPath == "" {
+			urlPath = "/"
+		}
+
+		if cfg.Browse && isDir(fsys, urlPath) && (cfg.IgnoreIndexes || !hasIndex(fsys, urlPath)) {
+			// Match net/http.FileServer: canonicalize to a trailing slash
+			// before rendering a listing, since every relative link in the
+			// listing (including "../") is resolved against this URL.
+			if !strings.HasSuffix(c.Request.URL.Path, "/") {
+				c.Set("Location", c.Request.URL.Path+"/")
+				return c.Status(http.StatusMovedPermanently).Send(nil)
+			}
+			return serveAutoindex(c.Response, c.Request, cfg, fsys, urlPath)
+		}
+
+		req := c.Request.Clone(c.Request.Context())
+		req.URL.Path = urlPath
+		fileServer.ServeHTTP(c.Response, req)
+		return nil
+	})
+}
+
+// toFileSystem adapts the root types Static accepts into an
+// http.FileSystem, the type serveAutoindex and net/http.FileServer both
+// talk to.
+func toFileSystem(root interface{}) http.FileSystem {
+	switch v := root.(type) {
+	case http.FileSystem:
+		return v
+	case fs.FS:
+		return http.FS(v)
+	case string:
+		return http.Dir(v)
+	default:
+		panic(fmt.Sprintf("quick: Static: unsupported root type %T", root))
+	}
+}
+
+// isDir reports whether urlPath (as seen through fsys) names a
+// directory, used to decide whether Static should consider rendering a
+// listing for it at all.
+func isDir(fsys http.FileSystem, urlPath string) bool {
+	f, err := fsys.Open(urlPath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	return err == nil && info.IsDir()
+}
+
+// listingEntry describes a single file or directory inside a listing.
+type listingEntry struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	SizeHum string    `json:"size_human"`
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"mod_time"`
+	IsDir   bool      `json:"is_dir"`
+}
+
+// listingPage is the data passed to the autoindex template.
+type listingPage struct {
+	Path    string
+	CanGoUp bool
+	Entries []listingEntry
+	Sort    string
+	Order   string
+}
+
+var defaultAutoindexTemplate = template.Must(template.New("autoindex").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Index of {{.Path}}</title></head>
+<body>
+<h1>Index of {{.Path}}</h1>
+<table>
+<thead><tr><th>Name</th><th>Size</th><th>Last Modified</th></tr></thead>
+<tbody>
+{{if .CanGoUp}}<tr><td><a href="../">../</a></td><td>-</td><td>-</td></tr>{{end}}
+{{range .Entries}}<tr><td><a href="{{.Name}}{{if .IsDir}}/{{end}}">{{.Name}}{{if .IsDir}}/{{end}}</a></td><td>{{.SizeHum}}</td><td>{{.ModTime.Format "2006-01-02 15:04:05"}}</td></tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+// serveAutoindex renders a directory listing for dir (read through fsys,
+// rooted the same way http.FileServer roots its http.FileSystem) at
+// urlPath, honoring cfg and the request's sort/order/Accept negotiation.
+func serveAutoindex(w http.ResponseWriter, r *http.Request, cfg StaticConfig, fsys http.FileSystem, urlPath string) error {
+	dir, err := fsys.Open(urlPath)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	infos, err := dir.Readdir(-1)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]listingEntry, 0, len(infos))
+	for _, info := range infos {
+		entries = append(entries, listingEntry{
+			Name:    info.Name(),
+			Size:    info.Size(),
+			SizeHum: humanSize(info.Size()),
+			Mode:    info.Mode().String(),
+			ModTime: info.ModTime(),
+			IsDir:   info.IsDir(),
+		})
+	}
+
+	sortBy := firstNonEmpty(r.URL.Query().Get("sort"), cfg.SortBy, "name")
+	order := firstNonEmpty(r.URL.Query().Get("order"), "asc")
+	sortEntries(entries, sortBy, order)
+
+	page := listingPage{
+		Path:    urlPath,
+		CanGoUp: urlPath != "/" && urlPath != "",
+		Entries: entries,
+		Sort:    sortBy,
+		Order:   order,
+	}
+
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		return json.NewEncoder(w).Encode(page)
+	}
+
+	tpl := cfg.Template
+	if tpl == nil {
+		tpl = defaultAutoindexTemplate
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	return tpl.Execute(w, page)
+}
+
+func sortEntries(entries []listingEntry, sortBy, order string) {
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "size":
+			return entries[i].Size < entries[j].Size
+		case "time":
+			return entries[i].ModTime.Before(entries[j].ModTime)
+		default:
+			return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if order == "desc" {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
+}
+
+// wantsJSON reports whether the client negotiated a JSON response via
+// the Accept header, used to switch the listing between HTML and JSON.
+func wantsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// hasIndex reports whether dir (as seen through fsys) contains an
+// index.html file, used to decide whether Static should fall back to
+// rendering a listing.
+func hasIndex(fsys http.FileSystem, dirPath string) bool {
+	f, err := fsys.Open(path.Join(dirPath, "index.html"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	return err == nil && !info.IsDir()
+}
+
+func humanSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
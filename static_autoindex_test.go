@@ -0,0 +1,181 @@
+package quick
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestServeAutoindex_SortAndOrder verifies that entries are sorted
+// according to the sort/order query params.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestServeAutoindex_SortAndOrder
+func TestServeAutoindex_SortAndOrder(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.txt", "a.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/static/?sort=name&order=desc", nil)
+	w := httptest.NewRecorder()
+
+	if err := serveAutoindex(w, r, StaticConfig{Browse: true}, http.Dir(dir), "/"); err != nil {
+		t.Fatalf("serveAutoindex: %v", err)
+	}
+
+	body := w.Body.String()
+	ia, ib, ic := indexOf(body, "a.txt"), indexOf(body, "b.txt"), indexOf(body, "c.txt")
+	if !(ic < ib && ib < ia) {
+		t.Errorf("expected descending name order c,b,a in output; got positions a=%d b=%d c=%d", ia, ib, ic)
+	}
+}
+
+// TestServeAutoindex_JSON verifies that an Accept: application/json
+// request gets a JSON listing instead of HTML.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestServeAutoindex_JSON
+func TestServeAutoindex_JSON(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/static/", nil)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+
+	if err := serveAutoindex(w, r, StaticConfig{Browse: true}, http.Dir(dir), "/"); err != nil {
+		t.Fatalf("serveAutoindex: %v", err)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+
+	var page listingPage
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Name != "file.txt" {
+		t.Errorf("unexpected entries: %+v", page.Entries)
+	}
+}
+
+// TestQuick_Static_Autoindex drives an actual request through
+// Quick.Static, verifying that a directory with Browse enabled and no
+// index.html answers with a generated listing instead of a 404.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_Static_Autoindex
+func TestQuick_Static_Autoindex(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	q := New()
+	q.Static("/static", dir, StaticConfig{Browse: true})
+
+	res, err := q.Qtest(QuickTestOptions{
+		Method: MethodGet,
+		URI:    "/static/",
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+
+	if res.StatusCode() != 200 {
+		t.Errorf("expected status 200, but got %d", res.StatusCode())
+	}
+	if !strings.Contains(res.BodyStr(), "file.txt") {
+		t.Errorf("expected listing body to mention file.txt, got %q", res.BodyStr())
+	}
+}
+
+// TestQuick_Static_Autoindex_RedirectsWithoutTrailingSlash verifies that
+// browsing a subdirectory without a trailing slash gets redirected to
+// the slash-terminated URL before the listing is rendered, the same way
+// net/http.FileServer does — otherwise every relative link in the
+// listing would resolve one level too high.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_Static_Autoindex_RedirectsWithoutTrailingSlash
+func TestQuick_Static_Autoindex_RedirectsWithoutTrailingSlash(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "file.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	q := New()
+	q.Static("/static", dir, StaticConfig{Browse: true})
+
+	res, err := q.Qtest(QuickTestOptions{
+		Method: MethodGet,
+		URI:    "/static/sub",
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+
+	if res.StatusCode() != 301 {
+		t.Errorf("expected status 301, but got %d", res.StatusCode())
+	}
+	if got := res.Response.Header.Get("Location"); got != "/static/sub/" {
+		t.Errorf("expected Location '/static/sub/', but got %q", got)
+	}
+}
+
+// TestQuick_Static_ServesFile verifies that Quick.Static still serves a
+// plain file request the usual way when no StaticConfig is given.
+//
+// Run with:
+//
+//	$ go test -v -run ^TestQuick_Static_ServesFile
+func TestQuick_Static_ServesFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("hi there"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	q := New()
+	q.Static("/static", dir)
+
+	res, err := q.Qtest(QuickTestOptions{
+		Method: MethodGet,
+		URI:    "/static/hello.txt",
+	})
+	if err != nil {
+		t.Fatalf("Qtest: %v", err)
+	}
+
+	if res.StatusCode() != 200 {
+		t.Errorf("expected status 200, but got %d", res.StatusCode())
+	}
+	if res.BodyStr() != "hi there" {
+		t.Errorf("expected body 'hi there', but got %q", res.BodyStr())
+	}
+}
+
+func indexOf(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i:i+len(substr)] == substr {
+			return i
+		}
+	}
+	return -1
+}